@@ -0,0 +1,72 @@
+package depgraph
+
+import (
+	"fmt"
+	"strings"
+)
+
+// CycleError is returned by TopSort when the graph contains one or more
+// cycles. Cycles holds one cycle for each strongly connected component of
+// size greater than one (or a single node with a self-loop), as the node keys
+// of a real edge path in actual edge order, so callers can render
+// diagnostics such as "A -> B -> C -> A" instead of a bare "cycle" error.
+// A component with several cycles is reported by one of them only, so a
+// reported cycle need not contain every node of its component.
+type CycleError struct {
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	if len(e.Cycles) == 1 {
+		cycle := e.Cycles[0]
+		return fmt.Sprintf("cycle detected: %s -> %s", strings.Join(cycle, " -> "), cycle[0])
+	}
+	return fmt.Sprintf("%d cycles detected", len(e.Cycles))
+}
+
+// toGraph returns a generic Graph[string, Keyer] with the same nodes and
+// edges as dg, so cycle detection isn't implemented twice: dg.cycles
+// below delegates to Graph's Tarjan-based tarjanSCCs/cyclePath (see
+// graph.go), the same algorithm Graph.TopSort uses for non-string keys.
+func (dg *DependencyGraph) toGraph() *Graph[string, Keyer] {
+	g := NewGraph[string, Keyer]()
+	for key, node := range dg.NodeMap {
+		g.AddNode(key, node.Value)
+	}
+	for key, node := range dg.NodeMap {
+		for to := range node.EdgesOut {
+			g.addEdge(key, to)
+		}
+	}
+	return g
+}
+
+// cycles finds dg's strongly connected components of size greater than one
+// (or size one with a self-loop) and returns one real edge path cycle
+// through each, which need not visit every node of the component - see
+// toGraph and Graph.tarjanSCCs.
+func (dg *DependencyGraph) cycles() [][]string {
+	return dg.toGraph().tarjanSCCs()
+}
+
+// fromGraph is toGraph's inverse: it converts g back into a
+// *DependencyGraph with the same nodes and edges. DependencyGraph's
+// non-cycle methods (Walk, the transitive reduction/closure operations,
+// the Ancestors/Descendants/Subgraph queries, and the DOT/JSON marshaling
+// in walk.go, reduction.go, query.go, and serialize.go) are thin wrappers
+// around the matching Graph[string, Keyer] method; fromGraph is how the
+// ones that return a graph hand the result back as a *DependencyGraph.
+func fromGraph(g *Graph[string, Keyer]) *DependencyGraph {
+	nodes := make([]Keyer, 0, len(g.order))
+	for _, key := range g.order {
+		value, _ := g.Value(key)
+		nodes = append(nodes, value)
+	}
+	dg := New(nodes)
+	for _, key := range g.order {
+		for dep := range g.dependencies[key] {
+			dg.addEdge(dg.NodeMap[key], dg.NodeMap[dep])
+		}
+	}
+	return dg
+}
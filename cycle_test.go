@@ -0,0 +1,105 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopSortCycleError(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("B")))
+	assert.Nil(graph.AddEdge(StringNode("B"), StringNode("C")))
+	assert.Nil(graph.AddEdge(StringNode("C"), StringNode("A")))
+
+	_, err := graph.TopSort()
+	assert.NotNil(err)
+
+	cycleErr, ok := err.(*CycleError)
+	assert.True(ok)
+	assert.Equal(1, len(cycleErr.Cycles))
+	assert.Equal([]string{"A", "B", "C"}, cycleErr.Cycles[0])
+}
+
+func TestTopSortSelfLoop(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+	}
+
+	graph := New(nodes)
+	node := graph.NodeMap["A"]
+	graph.addEdge(node, node)
+
+	_, err := graph.TopSort()
+	assert.NotNil(err)
+
+	cycleErr, ok := err.(*CycleError)
+	assert.True(ok)
+	assert.Equal([][]string{{"A"}}, cycleErr.Cycles)
+}
+
+// TestTopSortCyclePathFollowsRealEdges guards against reporting an
+// alphabetically-sorted node list as the cycle path: here the real cycle
+// is A -> C -> B -> A, which is not alphabetical order, and there is no
+// edge A -> B or B -> C in the graph at all.
+func TestTopSortCyclePathFollowsRealEdges(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("C")))
+	assert.Nil(graph.AddEdge(StringNode("C"), StringNode("B")))
+	assert.Nil(graph.AddEdge(StringNode("B"), StringNode("A")))
+
+	_, err := graph.TopSort()
+	assert.NotNil(err)
+
+	cycleErr, ok := err.(*CycleError)
+	assert.True(ok)
+	assert.Equal(1, len(cycleErr.Cycles))
+	assert.Equal([]string{"A", "C", "B"}, cycleErr.Cycles[0])
+	assert.Equal("cycle detected: A -> C -> B -> A", cycleErr.Error())
+}
+
+func TestTopSortMultipleCycles(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+		StringNode("D"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("B")))
+	assert.Nil(graph.AddEdge(StringNode("B"), StringNode("A")))
+	assert.Nil(graph.AddEdge(StringNode("C"), StringNode("D")))
+	assert.Nil(graph.AddEdge(StringNode("D"), StringNode("C")))
+
+	_, err := graph.TopSort()
+	assert.NotNil(err)
+
+	cycleErr, ok := err.(*CycleError)
+	assert.True(ok)
+	assert.Equal(2, len(cycleErr.Cycles))
+}
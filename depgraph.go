@@ -87,7 +87,7 @@ func (dg *DependencyGraph) TopSort() ([]string, error) {
 	// if there are any edges left, we have a cycle
 	for _, n := range copy.NodeMap {
 		if len(n.EdgesIn) > 0 || len(n.EdgesOut) > 0 {
-			return nil, errors.New("Cycle!")
+			return nil, &CycleError{Cycles: copy.cycles()}
 		}
 	}
 	return sorted, nil
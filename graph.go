@@ -0,0 +1,665 @@
+package depgraph
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// NodeSet is a set of node keys.
+type NodeSet[K comparable] map[K]bool
+
+// DepMap records, for each node key, the set of node keys on the other
+// side of an edge. Graph keeps one DepMap for dependencies (child ->
+// parents it depends on) and one for dependents (parent -> children that
+// depend on it).
+type DepMap[K comparable] map[K]NodeSet[K]
+
+// NodeInfo holds the payload attached to a node, so callers can retrieve
+// it without an interface assertion.
+type NodeInfo[V any] struct {
+	Value V
+}
+
+// Graph is a generic directed dependency graph: K is the node key type
+// (e.g. a string or an integer ID) and V is the per-node payload type.
+// It is modeled on yay's topo.Graph: node identity (NodeSet), edges
+// (DepMap) and per-node payload (NodeInfo) are tracked separately, so
+// callers working with non-string keys don't lose type information or
+// pay for interface boxing on every Value access.
+//
+// Graph is the implementation DependencyGraph's Keyer-based API is now
+// built on for the hard parts shared between both (cycle detection, in
+// particular - see toGraph in cycle.go): DependencyGraph stays a thin,
+// backward-compatible shim over Keyer for existing string-keyed callers,
+// while Graph is the generic entry point for everything else, including
+// non-string keys.
+type Graph[K comparable, V any] struct {
+	nodes        NodeSet[K]
+	dependencies DepMap[K]
+	dependents   DepMap[K]
+	nodeInfo     map[K]*NodeInfo[V]
+	order        []K // insertion order, so iteration stays deterministic for any K
+}
+
+// NewGraph creates a new, empty generic graph.
+func NewGraph[K comparable, V any]() *Graph[K, V] {
+	return &Graph[K, V]{
+		nodes:        make(NodeSet[K]),
+		dependencies: make(DepMap[K]),
+		dependents:   make(DepMap[K]),
+		nodeInfo:     make(map[K]*NodeInfo[V]),
+	}
+}
+
+// AddNode adds a node with the given key and payload. Adding a key that
+// already exists replaces its payload but leaves its edges intact.
+func (g *Graph[K, V]) AddNode(key K, value V) {
+	if !g.nodes[key] {
+		g.nodes[key] = true
+		g.dependencies[key] = make(NodeSet[K])
+		g.dependents[key] = make(NodeSet[K])
+		g.order = append(g.order, key)
+	}
+	g.nodeInfo[key] = &NodeInfo[V]{Value: value}
+}
+
+// Value returns the payload attached to key, and whether key is in the
+// graph at all.
+func (g *Graph[K, V]) Value(key K) (V, bool) {
+	info, ok := g.nodeInfo[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return info.Value, true
+}
+
+// addEdge is the unchecked edge-insertion primitive AddEdge validates
+// before calling; unlike AddEdge it permits a self-loop, mirroring
+// DependencyGraph's own addEdge/AddEdge split (see toGraph in cycle.go,
+// which relies on that to represent pathological self-loops).
+func (g *Graph[K, V]) addEdge(dependent K, dependency K) {
+	g.dependencies[dependent][dependency] = true
+	g.dependents[dependency][dependent] = true
+}
+
+// AddEdge records that dependent depends on dependency: dependent sorts
+// before dependency in TopSort, mirroring DependencyGraph.AddDependency.
+func (g *Graph[K, V]) AddEdge(dependent K, dependency K) error {
+	if dependent == dependency {
+		return fmt.Errorf("dependent and dependency cannot be the same node: %v", dependent)
+	}
+	if !g.nodes[dependent] {
+		return fmt.Errorf("dependent node %v not found", dependent)
+	}
+	if !g.nodes[dependency] {
+		return fmt.Errorf("dependency node %v not found", dependency)
+	}
+
+	g.addEdge(dependent, dependency)
+	return nil
+}
+
+// AddDependenciesForNode adds an edge from dependent to each key in
+// dependencies.
+func (g *Graph[K, V]) AddDependenciesForNode(dependent K, dependencies []K) error {
+	for _, dependency := range dependencies {
+		if err := g.AddEdge(dependent, dependency); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GraphCycleError reports the cycles found by a generic Graph's TopSort.
+// It plays the same role as CycleError does for the Keyer-based API -
+// see CycleError's doc comment - but carries cycles as slices of the
+// graph's own key type.
+type GraphCycleError[K comparable] struct {
+	Cycles [][]K
+}
+
+func (e *GraphCycleError[K]) Error() string {
+	if len(e.Cycles) == 1 {
+		parts := make([]string, len(e.Cycles[0]))
+		for i, k := range e.Cycles[0] {
+			parts[i] = fmt.Sprintf("%v", k)
+		}
+		return fmt.Sprintf("cycle detected: %s -> %s", strings.Join(parts, " -> "), parts[0])
+	}
+	return fmt.Sprintf("%d cycles detected", len(e.Cycles))
+}
+
+// TopSort returns the node keys of g in topological order - dependent
+// nodes before the nodes they depend on - mirroring
+// DependencyGraph.TopSort. If g contains a cycle, it returns a
+// *GraphCycleError.
+func (g *Graph[K, V]) TopSort() ([]K, error) {
+	// Kahn's algorithm, counting down each node's remaining (not yet
+	// output) dependents rather than its dependencies: a node is only
+	// ready once everything that depends on it has already been output,
+	// matching DependencyGraph.TopSort's "dependent before dependency"
+	// order. Counts are kept in a throwaway map so g itself is left
+	// untouched.
+	remaining := make(map[K]int, len(g.order))
+	for _, key := range g.order {
+		remaining[key] = len(g.dependents[key])
+	}
+
+	queue := make([]K, 0, len(g.order))
+	for _, key := range g.order {
+		if remaining[key] == 0 {
+			queue = append(queue, key)
+		}
+	}
+
+	sorted := make([]K, 0, len(g.order))
+	for len(queue) > 0 {
+		key := queue[0]
+		queue = queue[1:]
+		sorted = append(sorted, key)
+
+		for _, dep := range g.order {
+			if !g.dependencies[key][dep] {
+				continue
+			}
+			remaining[dep]--
+			if remaining[dep] == 0 {
+				queue = append(queue, dep)
+			}
+		}
+	}
+
+	if len(sorted) != len(g.order) {
+		return nil, &GraphCycleError[K]{Cycles: g.tarjanSCCs()}
+	}
+
+	return sorted, nil
+}
+
+// sortedKeys returns a copy of keys sorted by their fmt.Sprintf("%v", .)
+// representation, which is the closest thing to a deterministic order
+// available for an arbitrary comparable K.
+func sortedKeys[K comparable](keys []K) []K {
+	out := append([]K(nil), keys...)
+	sort.Slice(out, func(i, j int) bool {
+		return fmt.Sprintf("%v", out[i]) < fmt.Sprintf("%v", out[j])
+	})
+	return out
+}
+
+func keySet[K comparable](set map[K]bool) []K {
+	out := make([]K, 0, len(set))
+	for k := range set {
+		out = append(out, k)
+	}
+	return out
+}
+
+// tarjanSCCs computes the strongly connected components of g using
+// Tarjan's algorithm, keyed on dependency edges (the same edges TopSort
+// uses), and reconstructs each one as a real cycle path via cyclePath.
+// Only components that represent an actual cycle are returned: size two
+// or more, or size one with a self-loop. Node keys are visited in sorted
+// order so the result is deterministic across runs - see CycleError and
+// cycle.go's dg.toGraph, which delegates the Keyer-based API's cycle
+// detection to this same implementation.
+func (g *Graph[K, V]) tarjanSCCs() [][]K {
+	index := 0
+	indices := make(map[K]int)
+	lowlink := make(map[K]int)
+	onStack := make(map[K]bool)
+	stack := make([]K, 0)
+	var cycles [][]K
+
+	keys := sortedKeys(g.order)
+
+	var strongconnect func(key K)
+	strongconnect = func(key K) {
+		indices[key] = index
+		lowlink[key] = index
+		index++
+		stack = append(stack, key)
+		onStack[key] = true
+
+		for _, w := range sortedKeys(keySet(g.dependencies[key])) {
+			if _, seen := indices[w]; !seen {
+				strongconnect(w)
+				if lowlink[w] < lowlink[key] {
+					lowlink[key] = lowlink[w]
+				}
+			} else if onStack[w] {
+				if indices[w] < lowlink[key] {
+					lowlink[key] = indices[w]
+				}
+			}
+		}
+
+		if lowlink[key] == indices[key] {
+			var scc []K
+			for {
+				n := len(stack) - 1
+				w := stack[n]
+				stack = stack[:n]
+				onStack[w] = false
+				scc = append(scc, w)
+				if w == key {
+					break
+				}
+			}
+			if len(scc) >= 2 || (len(scc) == 1 && g.dependencies[key][key]) {
+				cycles = append(cycles, g.cyclePath(scc))
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if _, seen := indices[key]; !seen {
+			strongconnect(key)
+		}
+	}
+
+	return cycles
+}
+
+// cyclePath reconstructs a real edge path through scc (a strongly
+// connected component of size >= 2, or a single self-looped node) that
+// demonstrates the cycle - e.g. ["A", "B", "C"] represents A -> B -> C ->
+// A. The starting node is the smallest key, for determinism; from there a
+// depth-first search with backtracking follows only real dependency
+// edges that stay within scc until it finds its way back to the start,
+// which is guaranteed to exist since scc is strongly connected. This is
+// the shared implementation behind both Graph.TopSort's cycle detection
+// and DependencyGraph.TopSort's (via toGraph in cycle.go).
+func (g *Graph[K, V]) cyclePath(scc []K) []K {
+	if len(scc) == 1 {
+		return scc
+	}
+
+	inSCC := make(map[K]bool, len(scc))
+	for _, k := range scc {
+		inSCC[k] = true
+	}
+	start := sortedKeys(scc)[0]
+
+	visited := make(map[K]bool)
+	var path []K
+
+	var dfs func(cur K) bool
+	dfs = func(cur K) bool {
+		path = append(path, cur)
+		visited[cur] = true
+
+		next := make([]K, 0, len(g.dependencies[cur]))
+		for w := range g.dependencies[cur] {
+			if inSCC[w] {
+				next = append(next, w)
+			}
+		}
+
+		for _, w := range sortedKeys(next) {
+			if w == start && len(path) > 1 {
+				return true
+			}
+			if !visited[w] && dfs(w) {
+				return true
+			}
+		}
+
+		path = path[:len(path)-1]
+		visited[cur] = false
+		return false
+	}
+
+	dfs(start)
+	return path
+}
+
+// GraphWalkFunc is called once per node during a Walk, after all of that
+// node's dependencies have completed successfully.
+type GraphWalkFunc[K comparable, V any] func(K, V) error
+
+// GraphWalkError reports the outcome of a failed Walk, mirroring
+// WalkError for the Keyer-based API.
+type GraphWalkError[K comparable] struct {
+	Failed  map[K]error
+	Skipped []K
+}
+
+func (e *GraphWalkError[K]) Error() string {
+	return fmt.Sprintf("walk: %d node(s) failed, %d node(s) skipped", len(e.Failed), len(e.Skipped))
+}
+
+// Walk runs fn once for every node in g, never running a node before all
+// of its dependencies have completed successfully - the generic
+// counterpart of DependencyGraph.Walk. See that method's doc comment for
+// the full contract (concurrency, skip propagation, cancellation).
+func (g *Graph[K, V]) Walk(ctx context.Context, concurrency int, fn GraphWalkFunc[K, V]) error {
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+
+	type nodeResult struct {
+		err     error
+		skipped bool
+	}
+
+	// done[key] is closed once key's result is ready, broadcasting to every
+	// dependent waiting on it. A single-value channel send, by contrast,
+	// only ever reaches one of possibly several dependents, so any node
+	// with more than one dependent would leave the rest blocked forever.
+	// The result itself lives in results, guarded by mu, since a closed
+	// channel carries no payload.
+	done := make(map[K]chan struct{}, len(g.order))
+	for _, key := range g.order {
+		done[key] = make(chan struct{})
+	}
+
+	var (
+		mu      sync.Mutex
+		results = make(map[K]nodeResult, len(g.order))
+		failed  = make(map[K]error)
+		skipped = make([]K, 0)
+	)
+
+	var wg sync.WaitGroup
+	for _, key := range g.order {
+		wg.Add(1)
+		go func(key K) {
+			defer wg.Done()
+
+			depFailed := false
+			for dep := range g.dependencies[key] {
+				<-done[dep]
+				mu.Lock()
+				res := results[dep]
+				mu.Unlock()
+				if res.err != nil || res.skipped {
+					depFailed = true
+				}
+			}
+
+			var result nodeResult
+			switch {
+			case depFailed:
+				result.skipped = true
+			case ctx.Err() != nil:
+				result.skipped = true
+			default:
+				acquired := false
+				select {
+				case sem <- struct{}{}:
+					acquired = true
+				case <-ctx.Done():
+					result.skipped = true
+				}
+				if acquired {
+					value, _ := g.Value(key)
+					result.err = fn(key, value)
+					<-sem
+				}
+			}
+
+			mu.Lock()
+			results[key] = result
+			if result.skipped {
+				skipped = append(skipped, key)
+			} else if result.err != nil {
+				failed[key] = result.err
+			}
+			mu.Unlock()
+
+			close(done[key])
+		}(key)
+	}
+
+	wg.Wait()
+
+	if len(failed) == 0 && len(skipped) == 0 {
+		return nil
+	}
+	return &GraphWalkError[K]{Failed: failed, Skipped: sortedKeys(skipped)}
+}
+
+// reachable returns the set of node keys reachable from key by following
+// via (either g.dependencies or g.dependents). key itself is included only
+// if it lies on a cycle.
+func (g *Graph[K, V]) reachable(key K, via DepMap[K]) map[K]bool {
+	visited := make(map[K]bool)
+	var visit func(K)
+	visit = func(k K) {
+		for next := range via[k] {
+			if !visited[next] {
+				visited[next] = true
+				visit(next)
+			}
+		}
+	}
+	visit(key)
+	return visited
+}
+
+// Ancestors returns the keys of every node that key transitively depends
+// on, mirroring DependencyGraph.Ancestors. The result is unordered.
+func (g *Graph[K, V]) Ancestors(key K) ([]K, error) {
+	if !g.nodes[key] {
+		return nil, fmt.Errorf("node %v not found", key)
+	}
+	return keySet(g.reachable(key, g.dependencies)), nil
+}
+
+// Descendants returns the keys of every node that transitively depends on
+// key, mirroring DependencyGraph.Descendants. The result is unordered.
+func (g *Graph[K, V]) Descendants(key K) ([]K, error) {
+	if !g.nodes[key] {
+		return nil, fmt.Errorf("node %v not found", key)
+	}
+	return keySet(g.reachable(key, g.dependents)), nil
+}
+
+// Subgraph returns the induced subgraph containing keys plus every node
+// that transitively depends on one of them, mirroring
+// DependencyGraph.Subgraph. Use AncestorSubgraph for the inverse.
+func (g *Graph[K, V]) Subgraph(keys []K) (*Graph[K, V], error) {
+	return g.subgraph(keys, g.dependents)
+}
+
+// AncestorSubgraph returns the induced subgraph containing keys plus
+// every node that one of them transitively depends on, mirroring
+// DependencyGraph.AncestorSubgraph.
+func (g *Graph[K, V]) AncestorSubgraph(keys []K) (*Graph[K, V], error) {
+	return g.subgraph(keys, g.dependencies)
+}
+
+func (g *Graph[K, V]) subgraph(rootKeys []K, via DepMap[K]) (*Graph[K, V], error) {
+	include := make(map[K]bool, len(rootKeys))
+	for _, key := range rootKeys {
+		if !g.nodes[key] {
+			return nil, fmt.Errorf("node %v not found", key)
+		}
+		include[key] = true
+		for k := range g.reachable(key, via) {
+			include[k] = true
+		}
+	}
+
+	sub := NewGraph[K, V]()
+	for _, key := range g.order {
+		if include[key] {
+			value, _ := g.Value(key)
+			sub.AddNode(key, value)
+		}
+	}
+	for key := range include {
+		for dep := range g.dependencies[key] {
+			if include[dep] {
+				if err := sub.AddEdge(key, dep); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return sub, nil
+}
+
+// TransitiveReductionE returns a new graph containing the same nodes as g
+// with the minimal set of edges that preserves the same reachability,
+// mirroring DependencyGraph.TransitiveReductionE. g must be a DAG.
+func (g *Graph[K, V]) TransitiveReductionE() (*Graph[K, V], error) {
+	if _, err := g.TopSort(); err != nil {
+		return nil, err
+	}
+
+	reach := make(map[K]map[K]bool, len(g.order))
+	for _, key := range g.order {
+		reach[key] = g.reachable(key, g.dependencies)
+	}
+
+	reduced := NewGraph[K, V]()
+	for _, key := range g.order {
+		value, _ := g.Value(key)
+		reduced.AddNode(key, value)
+	}
+
+	for _, key := range g.order {
+		for dep := range g.dependencies[key] {
+			redundant := false
+			for other := range g.dependencies[key] {
+				if other != dep && reach[other][dep] {
+					redundant = true
+					break
+				}
+			}
+			if !redundant {
+				if err := reduced.AddEdge(key, dep); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return reduced, nil
+}
+
+// TransitiveReduction is like TransitiveReductionE but panics if g
+// contains a cycle.
+func (g *Graph[K, V]) TransitiveReduction() *Graph[K, V] {
+	reduced, err := g.TransitiveReductionE()
+	if err != nil {
+		panic(err)
+	}
+	return reduced
+}
+
+// TransitiveClosureE returns a new graph containing the same nodes as g
+// with an edge (u, v) added whenever v is reachable from u, mirroring
+// DependencyGraph.TransitiveClosureE. g must be a DAG.
+func (g *Graph[K, V]) TransitiveClosureE() (*Graph[K, V], error) {
+	if _, err := g.TopSort(); err != nil {
+		return nil, err
+	}
+
+	closure := NewGraph[K, V]()
+	for _, key := range g.order {
+		value, _ := g.Value(key)
+		closure.AddNode(key, value)
+	}
+
+	for _, key := range g.order {
+		for dep := range g.reachable(key, g.dependencies) {
+			if err := closure.AddEdge(key, dep); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return closure, nil
+}
+
+// TransitiveClosure is like TransitiveClosureE but panics if g contains a
+// cycle.
+func (g *Graph[K, V]) TransitiveClosure() *Graph[K, V] {
+	closure, err := g.TransitiveClosureE()
+	if err != nil {
+		panic(err)
+	}
+	return closure
+}
+
+// GraphDOTOptions controls how Graph.MarshalDOT renders a graph,
+// mirroring DOTOptions for the Keyer-based API.
+type GraphDOTOptions[K comparable, V any] struct {
+	// NodeAttrs, if set, is called once per node to produce the Graphviz
+	// attributes rendered in that node's [key=value, ...] block.
+	NodeAttrs func(K, V) map[string]string
+}
+
+// MarshalDOT writes g to w as a Graphviz "digraph", mirroring
+// DependencyGraph.MarshalDOT. Node keys are rendered via fmt.Sprintf("%v",
+// ...), quoted and escaped as Graphviz identifiers.
+func (g *Graph[K, V]) MarshalDOT(w io.Writer, opts *GraphDOTOptions[K, V]) error {
+	keys := sortedKeys(g.order)
+
+	if _, err := fmt.Fprintln(w, "digraph {"); err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		attrs := ""
+		if opts != nil && opts.NodeAttrs != nil {
+			value, _ := g.Value(key)
+			if a := opts.NodeAttrs(key, value); len(a) > 0 {
+				attrs = " [" + formatDOTAttrs(a) + "]"
+			}
+		}
+		if _, err := fmt.Fprintf(w, "  %s%s;\n", dotQuote(fmt.Sprintf("%v", key)), attrs); err != nil {
+			return err
+		}
+	}
+
+	for _, key := range keys {
+		for _, dep := range sortedKeys(keySet(g.dependencies[key])) {
+			from, to := dotQuote(fmt.Sprintf("%v", key)), dotQuote(fmt.Sprintf("%v", dep))
+			if _, err := fmt.Fprintf(w, "  %s -> %s;\n", from, to); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+// MarshalJSON serializes g using the same stable {nodes, edges} shape as
+// DependencyGraph.MarshalJSON, with keys rendered via fmt.Sprintf("%v",
+// ...). There is no generic UnmarshalJSON: K and V can't be reconstructed
+// from strings without knowing their concrete types.
+func (g *Graph[K, V]) MarshalJSON() ([]byte, error) {
+	keys := sortedKeys(g.order)
+
+	jg := jsonGraph{
+		Nodes: make([]string, 0, len(keys)),
+		Edges: make([]jsonEdge, 0),
+	}
+	for _, key := range keys {
+		jg.Nodes = append(jg.Nodes, fmt.Sprintf("%v", key))
+	}
+	for _, key := range keys {
+		for _, dep := range sortedKeys(keySet(g.dependencies[key])) {
+			jg.Edges = append(jg.Edges, jsonEdge{
+				From: fmt.Sprintf("%v", key),
+				To:   fmt.Sprintf("%v", dep),
+			})
+		}
+	}
+
+	return json.Marshal(jg)
+}
@@ -0,0 +1,188 @@
+package depgraph
+
+import (
+	"bytes"
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGraphTopSort(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+	g.AddNode("B", 2)
+	g.AddNode("C", 3)
+
+	assert.Nil(g.AddDependenciesForNode("C", []string{"A", "B"}))
+	assert.Nil(g.AddDependenciesForNode("B", []string{"A"}))
+
+	sorted, err := g.TopSort()
+	assert.Nil(err)
+	assert.Equal([]string{"C", "B", "A"}, sorted)
+
+	value, ok := g.Value("B")
+	assert.True(ok)
+	assert.Equal(2, value)
+
+	_, ok = g.Value("missing")
+	assert.False(ok)
+}
+
+func TestGraphTopSortCycle(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+	g.AddNode("B", 2)
+
+	assert.Nil(g.AddEdge("A", "B"))
+	assert.Nil(g.AddEdge("B", "A"))
+
+	_, err := g.TopSort()
+	assert.NotNil(err)
+	cycleErr, ok := err.(*GraphCycleError[string])
+	assert.True(ok)
+	assert.Equal([][]string{{"A", "B"}}, cycleErr.Cycles)
+}
+
+func TestGraphIntKeys(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[int, string]()
+	g.AddNode(1, "one")
+	g.AddNode(2, "two")
+	assert.Nil(g.AddEdge(2, 1))
+
+	sorted, err := g.TopSort()
+	assert.Nil(err)
+	assert.Equal([]int{2, 1}, sorted)
+}
+
+func TestGraphEdgeErrors(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+
+	assert.NotNil(g.AddEdge("A", "A"))
+	assert.NotNil(g.AddEdge("A", "missing"))
+	assert.NotNil(g.AddEdge("missing", "A"))
+}
+
+func TestGraphWalk(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+	g.AddNode("B", 2)
+	assert.Nil(g.AddEdge("B", "A"))
+
+	var order []string
+	err := g.Walk(context.Background(), 2, func(key string, value int) error {
+		order = append(order, key)
+		return nil
+	})
+	assert.Nil(err)
+	assert.Equal([]string{"A", "B"}, order)
+}
+
+func TestGraphWalkFanIn(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	// D depends on both B and C, which both depend on A - the same diamond
+	// fixture query_test.go's TestAncestorsAndDescendants/TestSubgraph
+	// use. A node with more than
+	// one dependent is exactly the shape that deadlocks if Walk's done
+	// channels aren't broadcast to every waiter.
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+	g.AddNode("B", 2)
+	g.AddNode("C", 3)
+	g.AddNode("D", 4)
+	assert.Nil(g.AddDependenciesForNode("D", []string{"B", "C"}))
+	assert.Nil(g.AddDependenciesForNode("B", []string{"A"}))
+	assert.Nil(g.AddDependenciesForNode("C", []string{"A"}))
+
+	var mu sync.Mutex
+	var order []string
+
+	err := g.Walk(context.Background(), 2, func(key string, value int) error {
+		mu.Lock()
+		order = append(order, key)
+		mu.Unlock()
+		return nil
+	})
+
+	assert.Nil(err)
+	assert.Equal(4, len(order))
+	assert.Equal("A", order[0])
+	assert.Equal("D", order[3])
+}
+
+func TestGraphAncestorsDescendantsSubgraph(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+	g.AddNode("B", 2)
+	g.AddNode("C", 3)
+	assert.Nil(g.AddDependenciesForNode("C", []string{"B"}))
+	assert.Nil(g.AddDependenciesForNode("B", []string{"A"}))
+
+	ancestors, err := g.Ancestors("C")
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"A", "B"}, ancestors)
+
+	descendants, err := g.Descendants("A")
+	assert.Nil(err)
+	assert.ElementsMatch([]string{"B", "C"}, descendants)
+
+	sub, err := g.AncestorSubgraph([]string{"C"})
+	assert.Nil(err)
+	assert.Equal(3, len(sub.order))
+}
+
+func TestGraphTransitiveReductionAndClosure(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+	g.AddNode("B", 2)
+	g.AddNode("C", 3)
+	assert.Nil(g.AddEdge("A", "B"))
+	assert.Nil(g.AddEdge("B", "C"))
+	assert.Nil(g.AddEdge("A", "C")) // redundant: C already reachable via B
+
+	reduced := g.TransitiveReduction()
+	assert.False(reduced.dependencies["A"]["C"])
+	assert.True(reduced.dependencies["A"]["B"])
+
+	closure := g.TransitiveClosure()
+	assert.True(closure.dependencies["A"]["C"])
+}
+
+func TestGraphMarshalDOT(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	g := NewGraph[string, int]()
+	g.AddNode("A", 1)
+	g.AddNode("B", 2)
+	assert.Nil(g.AddEdge("A", "B"))
+
+	var buf bytes.Buffer
+	assert.Nil(g.MarshalDOT(&buf, nil))
+	assert.Contains(buf.String(), `"A" -> "B";`)
+}
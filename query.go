@@ -0,0 +1,42 @@
+package depgraph
+
+// Ancestors returns the keys of every node that key transitively depends
+// on, i.e. everything reachable from key by following EdgesOut. The
+// result is unordered.
+//
+// Ancestors is a thin wrapper around Graph[string, Keyer].Ancestors (see
+// toGraph in cycle.go), so there's one implementation shared with the
+// generic API.
+func (dg *DependencyGraph) Ancestors(key string) ([]string, error) {
+	return dg.toGraph().Ancestors(key)
+}
+
+// Descendants returns the keys of every node that transitively depends on
+// key, i.e. everything that would need to be rebuilt if key changed. The
+// result is unordered.
+func (dg *DependencyGraph) Descendants(key string) ([]string, error) {
+	return dg.toGraph().Descendants(key)
+}
+
+// Subgraph returns the induced subgraph containing keys plus every node
+// that transitively depends on one of them (as returned by Descendants),
+// with edges restricted to that node set. Use AncestorSubgraph for the
+// inverse: keys plus everything they transitively depend on.
+func (dg *DependencyGraph) Subgraph(keys []string) (*DependencyGraph, error) {
+	sub, err := dg.toGraph().Subgraph(keys)
+	if err != nil {
+		return nil, err
+	}
+	return fromGraph(sub), nil
+}
+
+// AncestorSubgraph returns the induced subgraph containing keys plus every
+// node that one of them transitively depends on (as returned by
+// Ancestors), with edges restricted to that node set.
+func (dg *DependencyGraph) AncestorSubgraph(keys []string) (*DependencyGraph, error) {
+	sub, err := dg.toGraph().AncestorSubgraph(keys)
+	if err != nil {
+		return nil, err
+	}
+	return fromGraph(sub), nil
+}
@@ -0,0 +1,68 @@
+package depgraph
+
+import (
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAncestorsAndDescendants(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+		StringNode("D"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddDependenciesForNode("D", []string{"B", "C"}))
+	assert.Nil(graph.AddDependenciesForNode("B", []string{"A"}))
+	assert.Nil(graph.AddDependenciesForNode("C", []string{"A"}))
+
+	ancestors, err := graph.Ancestors("D")
+	assert.Nil(err)
+	sort.Strings(ancestors)
+	assert.Equal([]string{"A", "B", "C"}, ancestors)
+
+	descendants, err := graph.Descendants("A")
+	assert.Nil(err)
+	sort.Strings(descendants)
+	assert.Equal([]string{"B", "C", "D"}, descendants)
+
+	_, err = graph.Ancestors("missing")
+	assert.NotNil(err)
+}
+
+func TestSubgraph(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+		StringNode("D"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddDependenciesForNode("D", []string{"B", "C"}))
+	assert.Nil(graph.AddDependenciesForNode("B", []string{"A"}))
+	assert.Nil(graph.AddDependenciesForNode("C", []string{"A"}))
+
+	sub, err := graph.AncestorSubgraph([]string{"D"})
+	assert.Nil(err)
+	assert.Equal(4, len(sub.NodeMap))
+	_, hasEdge := sub.NodeMap["D"].EdgesOut["B"]
+	assert.True(hasEdge)
+
+	sub, err = graph.Subgraph([]string{"A"})
+	assert.Nil(err)
+	assert.Equal(4, len(sub.NodeMap))
+
+	_, err = graph.Subgraph([]string{"missing"})
+	assert.NotNil(err)
+}
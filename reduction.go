@@ -0,0 +1,48 @@
+package depgraph
+
+// TransitiveReductionE returns a new graph containing the same nodes as dg
+// with the minimal set of edges that preserves the same reachability: an
+// edge (u, v) is dropped whenever some other path from u to v already
+// exists. dg must be a DAG; if it contains a cycle, TransitiveReductionE
+// returns the *CycleError produced by TopSort.
+//
+// The reduction itself is delegated to Graph[string, Keyer] (see toGraph
+// and fromGraph in cycle.go), so there's one implementation shared with
+// the generic API.
+func (dg *DependencyGraph) TransitiveReductionE() (*DependencyGraph, error) {
+	if _, err := dg.TopSort(); err != nil {
+		return nil, err
+	}
+	return fromGraph(dg.toGraph().TransitiveReduction()), nil
+}
+
+// TransitiveReduction is like TransitiveReductionE but panics if dg
+// contains a cycle.
+func (dg *DependencyGraph) TransitiveReduction() *DependencyGraph {
+	reduced, err := dg.TransitiveReductionE()
+	if err != nil {
+		panic(err)
+	}
+	return reduced
+}
+
+// TransitiveClosureE returns a new graph containing the same nodes as dg
+// with an edge (u, v) added whenever v is reachable from u - the inverse of
+// TransitiveReductionE. dg must be a DAG; if it contains a cycle,
+// TransitiveClosureE returns the *CycleError produced by TopSort.
+func (dg *DependencyGraph) TransitiveClosureE() (*DependencyGraph, error) {
+	if _, err := dg.TopSort(); err != nil {
+		return nil, err
+	}
+	return fromGraph(dg.toGraph().TransitiveClosure()), nil
+}
+
+// TransitiveClosure is like TransitiveClosureE but panics if dg contains a
+// cycle.
+func (dg *DependencyGraph) TransitiveClosure() *DependencyGraph {
+	closure, err := dg.TransitiveClosureE()
+	if err != nil {
+		panic(err)
+	}
+	return closure
+}
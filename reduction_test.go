@@ -0,0 +1,82 @@
+package depgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransitiveReduction(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+		StringNode("D"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("B")))
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("C")))
+	assert.Nil(graph.AddEdge(StringNode("B"), StringNode("D")))
+	assert.Nil(graph.AddEdge(StringNode("C"), StringNode("D")))
+	// Redundant: D is already reachable from A via B and C.
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("D")))
+
+	reduced := graph.TransitiveReduction()
+
+	assert.Equal(4, len(reduced.NodeMap))
+	_, hasDirect := reduced.NodeMap["A"].EdgesOut["D"]
+	assert.False(hasDirect)
+	assert.Equal(2, len(reduced.NodeMap["A"].EdgesOut))
+
+	sorted, err := reduced.TopSort()
+	assert.Nil(err)
+	assert.Equal(len(nodes), len(sorted))
+}
+
+func TestTransitiveReductionCycle(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("B")))
+	assert.Nil(graph.AddEdge(StringNode("B"), StringNode("A")))
+
+	_, err := graph.TransitiveReductionE()
+	assert.NotNil(err)
+	_, ok := err.(*CycleError)
+	assert.True(ok)
+
+	assert.Panics(func() {
+		graph.TransitiveReduction()
+	})
+}
+
+func TestTransitiveClosure(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("B")))
+	assert.Nil(graph.AddEdge(StringNode("B"), StringNode("C")))
+
+	closure := graph.TransitiveClosure()
+
+	_, hasDirect := closure.NodeMap["A"].EdgesOut["C"]
+	assert.True(hasDirect)
+	assert.Equal(2, len(closure.NodeMap["A"].EdgesOut))
+}
@@ -0,0 +1,104 @@
+package depgraph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// DOTOptions controls how MarshalDOT renders a graph.
+type DOTOptions struct {
+	// NodeAttrs, if set, is called once per node to produce the Graphviz
+	// attributes (e.g. "color", "label") rendered in that node's
+	// [key=value, ...] block. A nil or empty result omits the block.
+	NodeAttrs func(Keyer) map[string]string
+}
+
+// MarshalDOT writes dg to w as a Graphviz "digraph", with one node
+// declaration per graph node and one edge statement per entry in
+// EdgesOut. Node keys are quoted and escaped as Graphviz identifiers.
+// opts may be nil, in which case nodes are emitted with no attributes.
+//
+// MarshalDOT is a thin wrapper around Graph[string, Keyer].MarshalDOT (see
+// toGraph in cycle.go), so there's one implementation shared with the
+// generic API.
+func (dg *DependencyGraph) MarshalDOT(w io.Writer, opts *DOTOptions) error {
+	var gOpts *GraphDOTOptions[string, Keyer]
+	if opts != nil && opts.NodeAttrs != nil {
+		gOpts = &GraphDOTOptions[string, Keyer]{
+			NodeAttrs: func(_ string, value Keyer) map[string]string {
+				return opts.NodeAttrs(value)
+			},
+		}
+	}
+	return dg.toGraph().MarshalDOT(w, gOpts)
+}
+
+func formatDOTAttrs(attrs map[string]string) string {
+	keys := make([]string, 0, len(attrs))
+	for k := range attrs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%s", k, dotQuote(attrs[k])))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `\"`) + `"`
+}
+
+// jsonGraph is the on-the-wire shape produced by MarshalJSON and consumed
+// by UnmarshalJSON.
+type jsonGraph struct {
+	Nodes []string   `json:"nodes"`
+	Edges []jsonEdge `json:"edges"`
+}
+
+type jsonEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// MarshalJSON serializes dg as a stable {nodes, edges} document: nodes is
+// the sorted list of node keys, and edges is the sorted list of
+// {from, to} pairs drawn from EdgesOut. Only node keys are persisted -
+// per-node Value payloads are not part of the Keyer contract and so
+// cannot be round-tripped generically; see UnmarshalJSON.
+//
+// MarshalJSON is a thin wrapper around Graph[string, Keyer].MarshalJSON
+// (see toGraph in cycle.go), so there's one implementation shared with the
+// generic API.
+func (dg *DependencyGraph) MarshalJSON() ([]byte, error) {
+	return dg.toGraph().MarshalJSON()
+}
+
+// UnmarshalJSON reconstructs dg from the {nodes, edges} document produced
+// by MarshalJSON. Nodes are recreated as StringNode values, since the
+// original Keyer implementation isn't recoverable from JSON alone.
+func (dg *DependencyGraph) UnmarshalJSON(data []byte) error {
+	var g jsonGraph
+	if err := json.Unmarshal(data, &g); err != nil {
+		return err
+	}
+
+	nodes := make([]Keyer, 0, len(g.Nodes))
+	for _, key := range g.Nodes {
+		nodes = append(nodes, StringNode(key))
+	}
+
+	dg.NodeMap = New(nodes).NodeMap
+	for _, edge := range g.Edges {
+		if err := dg.AddEdge(StringNode(edge.From), StringNode(edge.To)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
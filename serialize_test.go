@@ -0,0 +1,64 @@
+package depgraph
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalDOT(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("B")))
+
+	var buf bytes.Buffer
+	err := graph.MarshalDOT(&buf, &DOTOptions{
+		NodeAttrs: func(k Keyer) map[string]string {
+			return map[string]string{"label": k.Key()}
+		},
+	})
+	assert.Nil(err)
+
+	out := buf.String()
+	assert.Contains(out, "digraph {")
+	assert.Contains(out, `"A" [label="A"];`)
+	assert.Contains(out, `"A" -> "B";`)
+}
+
+func TestMarshalUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddEdge(StringNode("A"), StringNode("B")))
+	assert.Nil(graph.AddEdge(StringNode("B"), StringNode("C")))
+
+	data, err := json.Marshal(graph)
+	assert.Nil(err)
+
+	var roundTripped DependencyGraph
+	assert.Nil(json.Unmarshal(data, &roundTripped))
+
+	assert.Equal(3, len(roundTripped.NodeMap))
+	_, hasEdge := roundTripped.NodeMap["A"].EdgesOut["B"]
+	assert.True(hasEdge)
+
+	sorted, err := roundTripped.TopSort()
+	assert.Nil(err)
+	assert.Equal([]string{"A", "B", "C"}, sorted)
+}
@@ -0,0 +1,48 @@
+package depgraph
+
+import (
+	"context"
+	"fmt"
+)
+
+// WalkFunc is called once per node during a Walk, after all of that node's
+// dependencies have completed successfully.
+type WalkFunc func(Keyer) error
+
+// WalkError reports the outcome of a failed Walk: the nodes whose WalkFunc
+// returned an error, and the nodes that were skipped, either because they
+// depend (directly or transitively) on a failed node or because ctx was
+// cancelled before they ran.
+type WalkError struct {
+	Failed  map[string]error
+	Skipped []string
+}
+
+func (e *WalkError) Error() string {
+	return fmt.Sprintf("walk: %d node(s) failed, %d node(s) skipped", len(e.Failed), len(e.Skipped))
+}
+
+// Walk runs fn once for every node in the graph, never running a node before
+// all of its dependencies have completed successfully. Up to concurrency
+// nodes run at once; concurrency <= 0 is treated as 1. The graph is assumed
+// to be acyclic - run TopSort first if that isn't already known.
+//
+// If fn returns an error for a node, every node that transitively depends on
+// it is skipped rather than run. If ctx is cancelled, no new nodes are
+// scheduled and any node still waiting on its dependencies is skipped; nodes
+// already running are left to finish. Walk returns a *WalkError describing
+// the failed and skipped nodes, or nil if every node ran successfully.
+//
+// Walk is a thin wrapper around Graph[string, Keyer].Walk (see toGraph and
+// fromGraph in cycle.go), so there's one Walk implementation shared with
+// the generic API.
+func (dg *DependencyGraph) Walk(ctx context.Context, concurrency int, fn WalkFunc) error {
+	err := dg.toGraph().Walk(ctx, concurrency, func(_ string, value Keyer) error {
+		return fn(value)
+	})
+	if err == nil {
+		return nil
+	}
+	walkErr := err.(*GraphWalkError[string])
+	return &WalkError{Failed: walkErr.Failed, Skipped: walkErr.Skipped}
+}
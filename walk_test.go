@@ -0,0 +1,129 @@
+package depgraph
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWalkRunsInDependencyOrder(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddDependenciesForNode("B", []string{"A"}))
+	assert.Nil(graph.AddDependenciesForNode("C", []string{"B"}))
+
+	var mu sync.Mutex
+	var order []string
+
+	err := graph.Walk(context.Background(), 2, func(k Keyer) error {
+		mu.Lock()
+		order = append(order, k.Key())
+		mu.Unlock()
+		return nil
+	})
+
+	assert.Nil(err)
+	assert.Equal([]string{"A", "B", "C"}, order)
+}
+
+func TestWalkSkipsDependents(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddDependenciesForNode("B", []string{"A"}))
+	assert.Nil(graph.AddDependenciesForNode("C", []string{"B"}))
+
+	boom := errors.New("boom")
+
+	err := graph.Walk(context.Background(), 2, func(k Keyer) error {
+		if k.Key() == "A" {
+			return boom
+		}
+		return nil
+	})
+
+	assert.NotNil(err)
+	walkErr, ok := err.(*WalkError)
+	assert.True(ok)
+	assert.Equal(boom, walkErr.Failed["A"])
+	assert.Equal([]string{"B", "C"}, walkErr.Skipped)
+}
+
+func TestWalkFanIn(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	// D depends on both B and C, which both depend on A - the same diamond
+	// fixture TestAncestorsAndDescendants/TestSubgraph use in
+	// query_test.go. A node with more than one dependent is exactly the
+	// shape that deadlocks if Walk's done channels aren't broadcast to
+	// every waiter.
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+		StringNode("C"),
+		StringNode("D"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddDependenciesForNode("D", []string{"B", "C"}))
+	assert.Nil(graph.AddDependenciesForNode("B", []string{"A"}))
+	assert.Nil(graph.AddDependenciesForNode("C", []string{"A"}))
+
+	var mu sync.Mutex
+	var order []string
+
+	err := graph.Walk(context.Background(), 2, func(k Keyer) error {
+		mu.Lock()
+		order = append(order, k.Key())
+		mu.Unlock()
+		return nil
+	})
+
+	assert.Nil(err)
+	assert.Equal(4, len(order))
+	assert.Equal("A", order[0])
+	assert.Equal("D", order[3])
+}
+
+func TestWalkCancellation(t *testing.T) {
+	t.Parallel()
+	assert := assert.New(t)
+
+	nodes := []Keyer{
+		StringNode("A"),
+		StringNode("B"),
+	}
+
+	graph := New(nodes)
+	assert.Nil(graph.AddDependenciesForNode("B", []string{"A"}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := graph.Walk(ctx, 1, func(k Keyer) error {
+		return nil
+	})
+
+	assert.NotNil(err)
+	_, ok := err.(*WalkError)
+	assert.True(ok)
+}